@@ -0,0 +1,286 @@
+package sqlite
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ---------------- Backup, Dump & Restore ----------------
+// Backup drives SQLite's online backup API so it is safe to run while the
+// app still has the database open, unlike a plain file copy. Dump instead
+// writes a portable .sql script (a la `sqlite3 .dump`), and Restore replays
+// one back into the live database.
+
+// Backup copies the live database into dest using SQLite's online backup API.
+func (sqls Sqlite) Backup(dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	destDB, err := sql.Open("sqlite3", dest)
+	if err != nil {
+		return fmt.Errorf("failed to open backup destination: %w", err)
+	}
+	defer destDB.Close()
+
+	ctx := context.Background()
+	srcConn, err := sqls.config.DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire source connection: %w", err)
+	}
+	defer srcConn.Close()
+
+	destConn, err := destDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire destination connection: %w", err)
+	}
+	defer destConn.Close()
+
+	return destConn.Raw(func(destDriverConn any) error {
+		destSQLiteConn, ok := destDriverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return errors.New("backup destination is not a sqlite3 connection")
+		}
+		return srcConn.Raw(func(srcDriverConn any) error {
+			srcSQLiteConn, ok := srcDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("backup source is not a sqlite3 connection")
+			}
+
+			backup, err := destSQLiteConn.Backup("main", srcSQLiteConn, "main")
+			if err != nil {
+				return fmt.Errorf("failed to start backup: %w", err)
+			}
+			defer backup.Close()
+
+			done, err := backup.Step(-1)
+			if err != nil {
+				return fmt.Errorf("backup step failed: %w", err)
+			}
+			if !done {
+				return errors.New("backup did not complete in a single step")
+			}
+			return nil
+		})
+	})
+}
+
+// Dump writes a portable SQL script of the live database to dest, similar
+// to the sqlite3 CLI's ".dump" command. If dest is empty, Config.DumpSQLPath
+// is used instead.
+func (sqls Sqlite) Dump(dest string) error {
+	if dest == "" {
+		dest = sqls.config.DumpSQLPath
+	}
+	if dest == "" {
+		return errors.New("Dump requires a destination path or Config.DumpSQLPath to be set")
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create dump directory: %w", err)
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to create dump file: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "PRAGMA foreign_keys=OFF;")
+	fmt.Fprintln(w, "BEGIN TRANSACTION;")
+
+	rows, err := sqls.config.DB.Query(`SELECT name, sql FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	var tables []string
+	for rows.Next() {
+		var name, schema string
+		if err := rows.Scan(&name, &schema); err != nil {
+			rows.Close()
+			return err
+		}
+		fmt.Fprintf(w, "%s;\n", schema)
+		tables = append(tables, name)
+	}
+	rowsErr := rows.Err()
+	rows.Close()
+	if rowsErr != nil {
+		return rowsErr
+	}
+
+	for _, table := range tables {
+		if err := dumpTableRows(w, sqls.config.DB, table); err != nil {
+			return fmt.Errorf("failed to dump table %q: %w", table, err)
+		}
+	}
+
+	// Indexes and views only depend on tables, which already exist above, so
+	// they can be emitted any time after the table loop. Triggers are
+	// emitted last, after the data, so Restore replaying the INSERT
+	// statements above doesn't fire them a second time.
+	if err := dumpSchemaObjects(w, sqls.config.DB, "index", "view"); err != nil {
+		return fmt.Errorf("failed to dump indexes/views: %w", err)
+	}
+	if err := dumpSchemaObjects(w, sqls.config.DB, "trigger"); err != nil {
+		return fmt.Errorf("failed to dump triggers: %w", err)
+	}
+
+	fmt.Fprintln(w, "COMMIT;")
+	return nil
+}
+
+// dumpSchemaObjects writes the CREATE statement for every sqlite_master
+// entry of the given type(s), in name order.
+func dumpSchemaObjects(w *bufio.Writer, db *sql.DB, types ...string) error {
+	placeholders := make([]string, len(types))
+	args := make([]any, len(types))
+	for i, t := range types {
+		placeholders[i] = "?"
+		args[i] = t
+	}
+	query := fmt.Sprintf(`SELECT sql FROM sqlite_master WHERE type IN (%s) AND name NOT LIKE 'sqlite_%%' AND sql IS NOT NULL ORDER BY name`, strings.Join(placeholders, ","))
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s;\n", schema)
+	}
+	return rows.Err()
+}
+
+func dumpTableRows(w *bufio.Writer, db *sql.DB, table string) error {
+	rows, err := db.Query(fmt.Sprintf("SELECT * FROM %q", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		literals := make([]string, len(columns))
+		for i, v := range values {
+			literals[i] = sqlLiteral(v)
+		}
+		fmt.Fprintf(w, "INSERT INTO %q VALUES(%s);\n", table, strings.Join(literals, ","))
+	}
+	return rows.Err()
+}
+
+func sqlLiteral(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return "'" + strings.ReplaceAll(string(val), "'", "''") + "'"
+	case string:
+		return "'" + strings.ReplaceAll(val, "'", "''") + "'"
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// Restore replays a SQL script (as produced by Dump) into the live database.
+func (sqls Sqlite) Restore(src string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read restore script: %w", err)
+	}
+
+	ctx := context.Background()
+	conn, err := sqls.config.DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for restore: %w", err)
+	}
+	defer conn.Close()
+
+	// PRAGMA foreign_keys is a no-op once a transaction is open, and Dump
+	// writes tables in alphabetical rather than dependency order, so it has
+	// to be toggled off here, before BEGIN, to let child-before-parent rows
+	// insert cleanly. The prior value is restored once the transaction ends.
+	var prevForeignKeys int
+	if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&prevForeignKeys); err != nil {
+		return fmt.Errorf("failed to read foreign_keys setting: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys=OFF"); err != nil {
+		return fmt.Errorf("failed to disable foreign_keys for restore: %w", err)
+	}
+	defer conn.ExecContext(ctx, fmt.Sprintf("PRAGMA foreign_keys=%d", prevForeignKeys))
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("failed to begin restore transaction: %w", err)
+	}
+
+	for _, stmt := range splitStatements(string(data)) {
+		upper := strings.ToUpper(stmt)
+		if upper == "BEGIN TRANSACTION" || upper == "COMMIT" || strings.HasPrefix(upper, "PRAGMA ") {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			conn.ExecContext(ctx, "ROLLBACK")
+			return fmt.Errorf("restore statement failed: %w", err)
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("failed to commit restore: %w", err)
+	}
+	return nil
+}
+
+// startBackupScheduler periodically rotates a timestamped backup into
+// Config.BackupPath until Shutdown cancels it.
+func (p *Sqlite) startBackupScheduler() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.backupCancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(p.config.BackupIntervalMinutes) * time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				dest := filepath.Join(p.config.BackupPath, fmt.Sprintf("%s-%s.db", p.config.DbName, time.Now().Format("20060102-150405")))
+				sqls := Sqlite{config: p.config, app: p.app}
+				if err := sqls.Backup(dest); err != nil && p.app != nil {
+					p.app.Logger.Error("scheduled backup failed", "error", err)
+				}
+			}
+		}
+	}()
+}
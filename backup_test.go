@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSqlLiteral(t *testing.T) {
+	cases := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"nil", nil, "NULL"},
+		{"string", "hello", "'hello'"},
+		{"string with quote", "it's fine", "'it''s fine'"},
+		{"bytes", []byte("blob"), "'blob'"},
+		{"int", 42, "42"},
+		{"float", 3.5, "3.5"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sqlLiteral(tc.in); got != tc.want {
+				t.Errorf("sqlLiteral(%#v) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestDumpRestoreRoundTrip exercises Dump followed by Restore against a
+// schema deliberately shaped to catch the two issues this covers: "articles"
+// sorts alphabetically before its FK parent "authors", and a trigger/view/
+// index must survive the round trip alongside the tables.
+func TestDumpRestoreRoundTrip(t *testing.T) {
+	src, err := sql.Open("sqlite3", "file:roundtrip-src?mode=memory&cache=shared&_foreign_keys=1")
+	if err != nil {
+		t.Fatalf("failed to open source db: %v", err)
+	}
+	defer src.Close()
+	src.SetMaxOpenConns(1)
+
+	schema := []string{
+		`CREATE TABLE authors (id INTEGER PRIMARY KEY, name TEXT)`,
+		`CREATE TABLE articles (id INTEGER PRIMARY KEY, author_id INTEGER NOT NULL REFERENCES authors(id), title TEXT)`,
+		`CREATE TABLE audit_log (id INTEGER PRIMARY KEY, article_id INTEGER)`,
+		`CREATE INDEX idx_articles_author ON articles(author_id)`,
+		`CREATE VIEW article_titles AS SELECT title FROM articles`,
+		`CREATE TRIGGER trg_article_audit AFTER INSERT ON articles BEGIN INSERT INTO audit_log (article_id) VALUES (NEW.id); END`,
+	}
+	for _, stmt := range schema {
+		if _, err := src.Exec(stmt); err != nil {
+			t.Fatalf("failed to set up schema (%q): %v", stmt, err)
+		}
+	}
+	if _, err := src.Exec(`INSERT INTO authors (id, name) VALUES (1, 'Ada')`); err != nil {
+		t.Fatalf("failed to seed authors: %v", err)
+	}
+	if _, err := src.Exec(`INSERT INTO articles (id, author_id, title) VALUES (1, 1, 'it''s; a test')`); err != nil {
+		t.Fatalf("failed to seed articles: %v", err)
+	}
+
+	dumpPath := filepath.Join(t.TempDir(), "dump.sql")
+	sqls := Sqlite{config: &Config{DB: src}}
+	if err := sqls.Dump(dumpPath); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	dst, err := sql.Open("sqlite3", "file:roundtrip-dst?mode=memory&cache=shared&_foreign_keys=1")
+	if err != nil {
+		t.Fatalf("failed to open destination db: %v", err)
+	}
+	defer dst.Close()
+	dst.SetMaxOpenConns(1)
+
+	restored := Sqlite{config: &Config{DB: dst}}
+	if err := restored.Restore(dumpPath); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	var title string
+	if err := dst.QueryRow(`SELECT title FROM articles WHERE id = 1`).Scan(&title); err != nil {
+		t.Fatalf("failed to read restored article: %v", err)
+	}
+	if want := "it's; a test"; title != want {
+		t.Errorf("restored title = %q, want %q", title, want)
+	}
+
+	var viewTitle string
+	if err := dst.QueryRow(`SELECT title FROM article_titles`).Scan(&viewTitle); err != nil {
+		t.Fatalf("restored view did not work: %v", err)
+	}
+
+	// The source trigger fires exactly once, when the article is inserted
+	// above, leaving one legitimate audit_log row that Dump/Restore must
+	// copy as data. If Restore recreated the trigger before replaying that
+	// INSERT, it would fire a second time and this count would be 2.
+	var auditCount int
+	if err := dst.QueryRow(`SELECT COUNT(*) FROM audit_log`).Scan(&auditCount); err != nil {
+		t.Fatalf("failed to read audit_log: %v", err)
+	}
+	if auditCount != 1 {
+		t.Errorf("audit_log count = %d, want 1 (trigger must not re-fire while Restore replays INSERTs)", auditCount)
+	}
+
+	var indexCount int
+	if err := dst.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_articles_author'`).Scan(&indexCount); err != nil {
+		t.Fatalf("failed to check restored index: %v", err)
+	}
+	if indexCount != 1 {
+		t.Errorf("expected idx_articles_author to be restored, got count %d", indexCount)
+	}
+
+	var fkEnabled int
+	if err := dst.QueryRow("PRAGMA foreign_keys").Scan(&fkEnabled); err != nil {
+		t.Fatalf("failed to read foreign_keys setting: %v", err)
+	}
+	if fkEnabled != 1 {
+		t.Errorf("foreign_keys = %d, want 1 (Restore must restore the prior setting)", fkEnabled)
+	}
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ---------------- Custom Driver Registration ----------------
+// Mirrors the pattern used by apps like GoBlog: each plugin instance
+// registers its own uniquely named sqlite3 driver so Config.ConnectHook
+// and Config.CustomFunctions can attach app-specific scalar functions
+// (regex, markdown rendering, text scoring, ...) without colliding with
+// other consumers of github.com/mattn/go-sqlite3 in the same process.
+
+// CustomFunction registers a Go function as a SQL scalar function, callable
+// from queries executed against this plugin's database.
+type CustomFunction struct {
+	Name string // SQL function name
+	Fn   any    // Go function, registered via (*sqlite3.SQLiteConn).RegisterFunc
+	Pure bool   // true if Fn is deterministic, letting SQLite cache/optimize calls
+}
+
+// registerDriver registers a uniquely named sqlite3 driver whose ConnectHook
+// installs Config.CustomFunctions and then defers to Config.ConnectHook, and
+// returns the driver name to pass to sql.Open.
+func (sqls *Sqlite) registerDriver() (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate driver name: %w", err)
+	}
+	driverName := "sqlite3_wailsplugin_" + hex.EncodeToString(suffix)
+
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			for _, fn := range sqls.config.CustomFunctions {
+				if err := conn.RegisterFunc(fn.Name, fn.Fn, fn.Pure); err != nil {
+					return fmt.Errorf("failed to register custom function %q: %w", fn.Name, err)
+				}
+			}
+			if sqls.config.ConnectHook != nil {
+				return sqls.config.ConnectHook(conn)
+			}
+			return nil
+		},
+	})
+
+	return driverName, nil
+}
@@ -0,0 +1,391 @@
+package sqlite
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ---------------- Migrations ----------------
+// A small sql-migrate style migration engine. Migrations can be supplied
+// in code via Config.Migrations, or as .sql files under Config.MigrationsFS
+// using "-- +migrate Up" / "-- +migrate Down" section markers. Applied
+// migrations are tracked in the schema_migrations table.
+
+// Migration is a single versioned schema change. ID determines apply order
+// (lexical sort), so file-based migrations are conventionally prefixed with
+// a zero-padded sequence number, e.g. "0001_create_users".
+type Migration struct {
+	ID   string
+	Up   []string
+	Down []string
+}
+
+// MigrationStatusEntry reports whether a known migration has been applied.
+type MigrationStatusEntry struct {
+	ID        string `json:"id"`
+	Applied   bool   `json:"applied"`
+	AppliedAt int64  `json:"appliedAt,omitempty"`
+}
+
+const migrationsTableDDL = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	id TEXT PRIMARY KEY,
+	applied_at INTEGER NOT NULL
+)`
+
+// Migrate applies every pending migration in lexical ID order.
+func (sqls Sqlite) Migrate() error {
+	return sqls.migrateTo("")
+}
+
+// MigrateTo applies pending migrations up to and including id.
+func (sqls Sqlite) MigrateTo(id string) error {
+	if id == "" {
+		return errors.New("MigrateTo requires a non-empty migration id")
+	}
+	return sqls.migrateTo(id)
+}
+
+// Rollback reverts the last steps applied migrations, most recent first.
+func (sqls Sqlite) Rollback(steps int) error {
+	if steps <= 0 {
+		return errors.New("rollback steps must be greater than zero")
+	}
+	if err := sqls.ensureMigrationsTable(); err != nil {
+		return err
+	}
+	migrations, err := sqls.loadMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := sqls.appliedMigrationIDs()
+	if err != nil {
+		return err
+	}
+
+	var appliedMigrations []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.ID]; ok {
+			appliedMigrations = append(appliedMigrations, m)
+		}
+	}
+	if steps > len(appliedMigrations) {
+		steps = len(appliedMigrations)
+	}
+	toRollback := appliedMigrations[len(appliedMigrations)-steps:]
+
+	ctx := context.Background()
+	conn, err := sqls.config.DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for rollback: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("failed to begin rollback transaction: %w", err)
+	}
+
+	for i := len(toRollback) - 1; i >= 0; i-- {
+		m := toRollback[i]
+		for _, stmt := range m.Down {
+			if _, err := conn.ExecContext(ctx, stmt); err != nil {
+				conn.ExecContext(ctx, "ROLLBACK")
+				return fmt.Errorf("rollback of migration %q failed: %w", m.ID, err)
+			}
+		}
+		if _, err := conn.ExecContext(ctx, "DELETE FROM schema_migrations WHERE id = ?", m.ID); err != nil {
+			conn.ExecContext(ctx, "ROLLBACK")
+			return fmt.Errorf("failed to remove migration record %q: %w", m.ID, err)
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("failed to commit rollback: %w", err)
+	}
+	return nil
+}
+
+// MigrationStatus reports every known migration and whether it has been applied.
+func (sqls Sqlite) MigrationStatus() ([]MigrationStatusEntry, error) {
+	if err := sqls.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+	migrations, err := sqls.loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := sqls.appliedMigrationIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatusEntry, 0, len(migrations))
+	for _, m := range migrations {
+		entry := MigrationStatusEntry{ID: m.ID}
+		if appliedAt, ok := applied[m.ID]; ok {
+			entry.Applied = true
+			entry.AppliedAt = appliedAt
+		}
+		status = append(status, entry)
+	}
+	return status, nil
+}
+
+func (sqls *Sqlite) migrateTo(targetID string) error {
+	if err := sqls.ensureMigrationsTable(); err != nil {
+		return err
+	}
+	migrations, err := sqls.loadMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := sqls.appliedMigrationIDs()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	conn, err := sqls.config.DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for migration: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("failed to begin migration transaction: %w", err)
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.ID]; ok {
+			if targetID != "" && m.ID == targetID {
+				break
+			}
+			continue
+		}
+		for _, stmt := range m.Up {
+			if _, err := conn.ExecContext(ctx, stmt); err != nil {
+				conn.ExecContext(ctx, "ROLLBACK")
+				return fmt.Errorf("migration %q failed: %w", m.ID, err)
+			}
+		}
+		if _, err := conn.ExecContext(ctx, "INSERT INTO schema_migrations (id, applied_at) VALUES (?, ?)", m.ID, time.Now().Unix()); err != nil {
+			conn.ExecContext(ctx, "ROLLBACK")
+			return fmt.Errorf("failed to record migration %q: %w", m.ID, err)
+		}
+		if targetID != "" && m.ID == targetID {
+			break
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("failed to commit migrations: %w", err)
+	}
+	return nil
+}
+
+func (sqls *Sqlite) ensureMigrationsTable() error {
+	if _, err := sqls.config.DB.Exec(migrationsTableDDL); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+func (sqls *Sqlite) appliedMigrationIDs() (map[string]int64, error) {
+	rows, err := sqls.config.DB.Query("SELECT id, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]int64)
+	for rows.Next() {
+		var id string
+		var appliedAt int64
+		if err := rows.Scan(&id, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[id] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// loadMigrations merges Config.Migrations with any .sql files found under
+// Config.MigrationsFS/Config.MigrationsDir and returns them sorted by ID.
+func (sqls *Sqlite) loadMigrations() ([]Migration, error) {
+	migrations := append([]Migration(nil), sqls.config.Migrations...)
+
+	if sqls.config.MigrationsFS != nil {
+		fileMigrations, err := loadMigrationsFromFS(sqls.config.MigrationsFS, sqls.config.MigrationsDir)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, fileMigrations...)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].ID < migrations[j].ID })
+	return migrations, nil
+}
+
+func loadMigrationsFromFS(migFS fs.FS, dir string) ([]Migration, error) {
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := fs.ReadDir(migFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations dir %q: %w", dir, err)
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		data, err := fs.ReadFile(migFS, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", entry.Name(), err)
+		}
+		up, down := parseMigrationFile(string(data))
+		migrations = append(migrations, Migration{
+			ID:   strings.TrimSuffix(entry.Name(), ".sql"),
+			Up:   up,
+			Down: down,
+		})
+	}
+	return migrations, nil
+}
+
+// parseMigrationFile splits a sql-migrate style file into Up/Down statement
+// lists using "-- +migrate Up" and "-- +migrate Down" section markers.
+func parseMigrationFile(contents string) (up []string, down []string) {
+	const (
+		sectionNone = iota
+		sectionUp
+		sectionDown
+	)
+	section := sectionNone
+	var upText, downText strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch trimmed := strings.TrimSpace(line); {
+		case strings.HasPrefix(trimmed, "-- +migrate Up"):
+			section = sectionUp
+			continue
+		case strings.HasPrefix(trimmed, "-- +migrate Down"):
+			section = sectionDown
+			continue
+		}
+		switch section {
+		case sectionUp:
+			upText.WriteString(line + "\n")
+		case sectionDown:
+			downText.WriteString(line + "\n")
+		}
+	}
+
+	return splitStatements(upText.String()), splitStatements(downText.String())
+}
+
+// splitStatements splits a block of SQL text into individual statements on
+// top-level semicolons. It tracks quoted string/identifier literals and
+// BEGIN...END nesting (e.g. a CREATE TRIGGER body) so that semicolons inside
+// either of those do not split a statement in two.
+func splitStatements(block string) []string {
+	var statements []string
+	var current strings.Builder
+	var word strings.Builder
+	var quote rune
+	depth := 0
+	pendingBegin := false
+
+	// resolveWord updates the BEGIN...END depth for one completed word. A
+	// bare "BEGIN" only opens a trigger body once we see that it isn't
+	// actually "BEGIN TRANSACTION"/"BEGIN IMMEDIATE"/"BEGIN DEFERRED"/
+	// "BEGIN EXCLUSIVE", which start a transaction instead and must not be
+	// mistaken for an unterminated trigger body.
+	resolveWord := func(w string) {
+		if pendingBegin {
+			pendingBegin = false
+			switch w {
+			case "TRANSACTION", "IMMEDIATE", "DEFERRED", "EXCLUSIVE":
+				return
+			}
+			depth++
+		}
+		switch w {
+		case "BEGIN":
+			pendingBegin = true
+		case "END":
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+	flushWord := func() {
+		if word.Len() > 0 {
+			resolveWord(strings.ToUpper(word.String()))
+			word.Reset()
+		}
+	}
+	isWordChar := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	runes := []rune(block)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if quote != 0 {
+			current.WriteRune(r)
+			if r == quote {
+				if i+1 < len(runes) && runes[i+1] == quote {
+					// Doubled quote char is an escaped quote, not the closer.
+					current.WriteRune(runes[i+1])
+					i++
+					continue
+				}
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			flushWord()
+			quote = r
+			current.WriteRune(r)
+		case isWordChar(r):
+			word.WriteRune(r)
+			current.WriteRune(r)
+		case r == ';':
+			// Flush the pending word first so a closing "END" has already
+			// brought depth back to 0 before this semicolon is evaluated.
+			flushWord()
+			if depth == 0 {
+				if stmt := strings.TrimSpace(current.String()); stmt != "" {
+					statements = append(statements, stmt)
+				}
+				current.Reset()
+			} else {
+				current.WriteRune(r)
+			}
+		default:
+			flushWord()
+			current.WriteRune(r)
+		}
+	}
+	flushWord()
+
+	if stmt := strings.TrimSpace(current.String()); stmt != "" {
+		statements = append(statements, stmt)
+	}
+	return statements
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatementsIgnoresSemicolonsInStringLiterals(t *testing.T) {
+	block := `INSERT INTO notes (body) VALUES ('hello; world'); INSERT INTO notes (body) VALUES ('it''s; fine');`
+	got := splitStatements(block)
+	want := []string{
+		`INSERT INTO notes (body) VALUES ('hello; world')`,
+		`INSERT INTO notes (body) VALUES ('it''s; fine')`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitStatements() = %#v, want %#v", got, want)
+	}
+}
+
+func TestSplitStatementsIgnoresSemicolonsInQuotedIdentifiers(t *testing.T) {
+	block := `SELECT * FROM "weird;table"; SELECT 1;`
+	got := splitStatements(block)
+	want := []string{
+		`SELECT * FROM "weird;table"`,
+		`SELECT 1`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitStatements() = %#v, want %#v", got, want)
+	}
+}
+
+func TestSplitStatementsKeepsTriggerBodyIntact(t *testing.T) {
+	block := `CREATE TRIGGER trg_audit AFTER INSERT ON notes BEGIN INSERT INTO audit (note_id) VALUES (NEW.id); UPDATE notes SET seen = 1 WHERE id = NEW.id; END; SELECT 1;`
+	got := splitStatements(block)
+	want := []string{
+		`CREATE TRIGGER trg_audit AFTER INSERT ON notes BEGIN INSERT INTO audit (note_id) VALUES (NEW.id); UPDATE notes SET seen = 1 WHERE id = NEW.id; END`,
+		`SELECT 1`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitStatements() = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseMigrationFileSplitsUpAndDownSections(t *testing.T) {
+	contents := `-- +migrate Up
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+INSERT INTO users (name) VALUES ('a;b');
+
+-- +migrate Down
+DROP TABLE users;
+`
+	up, down := parseMigrationFile(contents)
+
+	wantUp := []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"INSERT INTO users (name) VALUES ('a;b')",
+	}
+	if !reflect.DeepEqual(up, wantUp) {
+		t.Fatalf("up = %#v, want %#v", up, wantUp)
+	}
+
+	wantDown := []string{"DROP TABLE users"}
+	if !reflect.DeepEqual(down, wantDown) {
+		t.Fatalf("down = %#v, want %#v", down, wantDown)
+	}
+}
+
+func TestParseMigrationFileWithNoMarkersProducesNoStatements(t *testing.T) {
+	up, down := parseMigrationFile("CREATE TABLE users (id INTEGER PRIMARY KEY);")
+	if len(up) != 0 || len(down) != 0 {
+		t.Fatalf("expected no statements outside of section markers, got up=%#v down=%#v", up, down)
+	}
+}
@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// ---------------- Data Directory Resolution ----------------
+// resolveDataDir follows the XDG Base Directory spec on Linux/BSD and each
+// platform's conventional app-data location elsewhere, matching the layout
+// most desktop apps already expect.
+
+// resolveDataDir returns the absolute directory that should hold the
+// database file, honoring the Config.*Dir overrides before falling back to
+// the platform default.
+func (sqls *Sqlite) resolveDataDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		if sqls.config.WindowsDir != "" {
+			return sqls.config.WindowsDir, nil
+		}
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			return "", errors.New("APPDATA is not set")
+		}
+		return filepath.Join(appData, sqls.config.DbName), nil
+
+	case "darwin":
+		if sqls.config.MacDir != "" {
+			return sqls.config.MacDir, nil
+		}
+		home := os.Getenv("HOME")
+		if home == "" {
+			return "", errors.New("HOME is not set")
+		}
+		return filepath.Join(home, "Library", "Application Support", sqls.config.DbName), nil
+
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if sqls.config.LinuxDir != "" {
+			return sqls.config.LinuxDir, nil
+		}
+		if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+			return filepath.Join(dataHome, sqls.config.DbName), nil
+		}
+		home := os.Getenv("HOME")
+		if home == "" {
+			return "", errors.New("HOME is not set")
+		}
+		return filepath.Join(home, ".local", "share", sqls.config.DbName), nil
+
+	default:
+		return "", errors.New("operating system not supported, please use Windows/macOS/Linux")
+	}
+}
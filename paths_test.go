@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestResolveDataDirHonorsConfigOverride(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("override paths are exercised per-OS; this covers the linux branch")
+	}
+	sqls := &Sqlite{config: &Config{DbName: "app", LinuxDir: "/custom/data/dir"}}
+	got, err := sqls.resolveDataDir()
+	if err != nil {
+		t.Fatalf("resolveDataDir() error = %v", err)
+	}
+	if got != "/custom/data/dir" {
+		t.Fatalf("resolveDataDir() = %q, want %q", got, "/custom/data/dir")
+	}
+}
+
+func TestResolveDataDirLinuxPrefersXDGDataHome(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("linux-specific XDG behavior")
+	}
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+	t.Setenv("HOME", "/home/someone")
+
+	sqls := &Sqlite{config: &Config{DbName: "app"}}
+	got, err := sqls.resolveDataDir()
+	if err != nil {
+		t.Fatalf("resolveDataDir() error = %v", err)
+	}
+	want := filepath.Join("/xdg/data", "app")
+	if got != want {
+		t.Fatalf("resolveDataDir() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveDataDirLinuxFallsBackToHomeLocalShare(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("linux-specific XDG fallback behavior")
+	}
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("HOME", "/home/someone")
+
+	sqls := &Sqlite{config: &Config{DbName: "app"}}
+	got, err := sqls.resolveDataDir()
+	if err != nil {
+		t.Fatalf("resolveDataDir() error = %v", err)
+	}
+	want := filepath.Join("/home/someone", ".local", "share", "app")
+	if got != want {
+		t.Fatalf("resolveDataDir() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveDataDirLinuxRequiresHomeWhenXDGUnset(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("linux-specific XDG fallback behavior")
+	}
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("HOME", "")
+
+	sqls := &Sqlite{config: &Config{DbName: "app"}}
+	if _, err := sqls.resolveDataDir(); err == nil {
+		t.Fatal("expected an error when neither XDG_DATA_HOME nor HOME is set")
+	}
+}
@@ -1,14 +1,18 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
-	"runtime"
+	"strconv"
+	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -23,25 +27,45 @@ type Config struct {
 	// Memory Database
 	InMemory bool // Default false
 	// File Locations
-	MacDir     string // XDG Default/DbName/DbName.db
-	WindowsDir string // XDG Default/DbName/DbName.db
-	LinuxDir   string // XDG Default/DbName/DbName.db
+	MacDir      string      // Default $HOME/Library/Application Support/DbName
+	WindowsDir  string      // Default %APPDATA%\DbName
+	LinuxDir    string      // Default $XDG_DATA_HOME/DbName, or $HOME/.local/share/DbName
+	DataDirMode os.FileMode // Default 0755, permissions used when creating the data directory
 	// Shutdown Options
 	DeleteOnShutdown bool // Default false
 	DeleteDir        bool // Default false
 	// Connection Options
 	CacheShared        bool    // Default false
-	MaxOpenConnections int     // Default 1
+	MaxOpenConnections int     // Default 1, or 4 when JournalMode is WAL
 	MaxIdleConnections int     // Default 2
 	DB                 *sql.DB // Connection created on Init
 	savedPath          string  // interal use for cleanup
+	// PRAGMA / DSN Tuning (file databases only; InMemory opens ":memory:" without these)
+	JournalMode     string            // "WAL"/"DELETE"/"MEMORY", sets _journal_mode
+	SynchronousMode string            // "NORMAL"/"FULL"/"OFF", sets _synchronous
+	BusyTimeoutMs   int               // sets _busy_timeout
+	ForeignKeys     bool              // sets _foreign_keys
+	TempStore       string            // sets _temp_store
+	ExtraDSNParams  map[string]string // Additional mattn/go-sqlite3 DSN params
+	// Migration Options
+	Migrations    []Migration // Applied in lexical ID order alongside MigrationsFS
+	MigrationsFS  fs.FS       // Optional .sql files, loaded from MigrationsDir
+	MigrationsDir string      // Default "." relative to MigrationsFS
+	// Driver Customization
+	ConnectHook     func(*sqlite3.SQLiteConn) error // Called on every new connection, after CustomFunctions are registered
+	CustomFunctions []CustomFunction                // Go functions exposed as SQL scalar functions
+	// Backup Options
+	BackupPath            string // Destination directory for scheduled backups
+	BackupIntervalMinutes int    // Default 0 (disabled)
+	DumpSQLPath           string // Default destination for Dump when no path is given
 }
 
 // Changing the name of this struct will change the name of the plugin in the frontend
 // Bound methods will exist inside frontend/bindings/sqlite/[PluginStruct]
 type Sqlite struct {
-	config *Config
-	app    *application.App
+	config       *Config
+	app          *application.App
+	backupCancel context.CancelFunc // cancels the scheduled backup goroutine, if running
 }
 
 func NewPlugin(config *Config) *Sqlite {
@@ -53,6 +77,9 @@ func NewPlugin(config *Config) *Sqlite {
 // Shutdown is called when the app is shutting down via runtime.Quit() call
 // You can use this to clean up any resources you have allocated
 func (p *Sqlite) Shutdown() error {
+	if p.backupCancel != nil {
+		p.backupCancel()
+	}
 	if p.config.DeleteOnShutdown {
 		// Delete Database
 		if p.config.InMemory {
@@ -83,12 +110,32 @@ func (p *Sqlite) Name() string {
 func (p *Sqlite) Init() error {
 	p.app = application.Get()
 	if p.config.InMemory {
-		return p.createMemDB()
+		if err := p.createMemDB(); err != nil {
+			return err
+		}
+	} else {
+		if p.config.DbName == "" {
+			return errors.New("Sqlite requires a DbName to be set or configured for In Memory database")
+		}
+		if err := p.createFileDB(); err != nil {
+			return err
+		}
 	}
-	if p.config.DbName == "" {
-		return errors.New("Sqlite requires a DbName to be set or configured for In Memory database")
+
+	if len(p.config.Migrations) > 0 || p.config.MigrationsFS != nil {
+		if err := p.migrateTo(""); err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
 	}
-	return p.createFileDB()
+
+	if _, err := p.config.DB.Exec("ANALYZE"); err != nil {
+		return fmt.Errorf("failed to analyze database: %w", err)
+	}
+
+	if p.config.BackupIntervalMinutes > 0 {
+		p.startBackupScheduler()
+	}
+	return nil
 }
 
 // ---------------- Plugin Methods ----------------
@@ -97,7 +144,7 @@ func (p *Sqlite) Init() error {
 // You can also return any type that is JSON serializable.
 // See https://golang.org/pkg/encoding/json/#Marshal for more information.
 func (sqls Sqlite) Execute(cmd string, args ...any) (int64, error) {
-	result, err := sqls.config.DB.Exec(cmd, args)
+	result, err := sqls.config.DB.Exec(cmd, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -109,7 +156,7 @@ func (sqls Sqlite) Execute(cmd string, args ...any) (int64, error) {
 }
 
 func (sqls Sqlite) Query(query string, args ...any) (*sql.Rows, error) {
-	return sqls.config.DB.Query(query, args)
+	return sqls.config.DB.Query(query, args...)
 }
 
 func (sqls Sqlite) GetDB() (*sql.DB, error) {
@@ -125,7 +172,12 @@ func (sqls *Sqlite) SetDB(newDB *sql.DB) error {
 }
 
 func (sqls *Sqlite) createMemDB() error {
-	db, err := sql.Open("sqlite3", ":memory:")
+	driverName, err := sqls.registerDriver()
+	if err != nil {
+		return err
+	}
+
+	db, err := sql.Open(driverName, ":memory:")
 	if err != nil {
 		return fmt.Errorf("failed to create in-memory database: %w", err)
 	}
@@ -135,46 +187,36 @@ func (sqls *Sqlite) createMemDB() error {
 }
 
 func (sqls *Sqlite) createFileDB() error {
-	var dbPath string
-	switch runtime.GOOS {
-	case "windows":
-		if sqls.config.WindowsDir != "" {
-			dbPath = sqls.config.WindowsDir
-		} else {
-			dbPath = filepath.Join(os.Getenv("APPDATA"), sqls.config.WindowsDir)
-		}
-	case "darwin":
-		if sqls.config.MacDir != "" {
-			dbPath = sqls.config.MacDir
-		} else {
-			dbPath = filepath.Join(os.Getenv("HOME"), "Library", "Application Support", sqls.config.DbName, sqls.config.DbName+".db")
-		}
-
-	case "linux":
-		if sqls.config.LinuxDir != "" {
-			dbPath = sqls.config.LinuxDir
-		} else {
-			dbPath = filepath.Join(os.Getenv("HOME"), ".config", sqls.config.DbName)
-		}
-	default:
-		return errors.New("operating system not supported, please use Windows/macOS/Linux")
+	dataDir, err := sqls.resolveDataDir()
+	if err != nil {
+		return err
 	}
-	fileName := sqls.config.DbName + ".db"
 
-	if sqls.config.CacheShared {
-		fileName = fileName + "?cache=shared"
+	mode := sqls.config.DataDirMode
+	if mode == 0 {
+		mode = 0755
 	}
 
 	// Create directory if it doesn't exist
-	err := os.MkdirAll(dbPath, 0755)
-	if err != nil {
+	if err := os.MkdirAll(dataDir, mode); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	// Create sqlite DB with name inside of Dir
-	dbPath = filepath.Join(dbPath, fileName)
-	sqls.config.savedPath = fileName
-	db, err := sql.Open("sqlite3", dbPath)
+	dbPath := filepath.Join(dataDir, sqls.config.DbName+".db")
+	sqls.config.savedPath = dbPath
+
+	dsn := dbPath
+	if params := sqls.buildDSNParams(); len(params) > 0 {
+		dsn = dbPath + "?" + params.Encode()
+	}
+
+	driverName, err := sqls.registerDriver()
+	if err != nil {
+		return err
+	}
+
+	db, err := sql.Open(driverName, dsn)
 	if err != nil {
 		return fmt.Errorf("failed to create database: %w", err)
 	}
@@ -186,7 +228,13 @@ func (sqls *Sqlite) createFileDB() error {
 		idle = 2
 	}
 	if open == 0 {
-		open = 1
+		if strings.EqualFold(sqls.config.JournalMode, "WAL") {
+			// WAL allows concurrent readers alongside a single writer, so it's
+			// safe to default to more than one connection.
+			open = 4
+		} else {
+			open = 1
+		}
 	}
 	db.SetMaxIdleConns(idle)
 	db.SetMaxOpenConns(open)
@@ -200,3 +248,33 @@ func (sqls *Sqlite) createFileDB() error {
 	sqls.config.DB = db
 	return nil
 }
+
+// buildDSNParams translates the PRAGMA/connection Config fields into the
+// mattn/go-sqlite3 DSN query parameters that apply them on every connection.
+func (sqls *Sqlite) buildDSNParams() url.Values {
+	params := url.Values{}
+
+	if sqls.config.CacheShared {
+		params.Set("cache", "shared")
+	}
+	if sqls.config.JournalMode != "" {
+		params.Set("_journal_mode", sqls.config.JournalMode)
+	}
+	if sqls.config.SynchronousMode != "" {
+		params.Set("_synchronous", sqls.config.SynchronousMode)
+	}
+	if sqls.config.BusyTimeoutMs > 0 {
+		params.Set("_busy_timeout", strconv.Itoa(sqls.config.BusyTimeoutMs))
+	}
+	if sqls.config.ForeignKeys {
+		params.Set("_foreign_keys", "true")
+	}
+	if sqls.config.TempStore != "" {
+		params.Set("_temp_store", sqls.config.TempStore)
+	}
+	for k, v := range sqls.config.ExtraDSNParams {
+		params.Set(k, v)
+	}
+
+	return params
+}
@@ -0,0 +1,48 @@
+package sqlite
+
+import "testing"
+
+func TestBuildDSNParamsOmitsUnsetFields(t *testing.T) {
+	sqls := &Sqlite{config: &Config{}}
+	params := sqls.buildDSNParams()
+	if len(params) != 0 {
+		t.Fatalf("expected no DSN params for a zero-value config, got %v", params)
+	}
+}
+
+func TestBuildDSNParamsAppliesPragmaTuning(t *testing.T) {
+	sqls := &Sqlite{config: &Config{
+		CacheShared:     true,
+		JournalMode:     "WAL",
+		SynchronousMode: "NORMAL",
+		BusyTimeoutMs:   5000,
+		ForeignKeys:     true,
+		TempStore:       "MEMORY",
+		ExtraDSNParams:  map[string]string{"_auto_vacuum": "FULL"},
+	}}
+
+	params := sqls.buildDSNParams()
+
+	want := map[string]string{
+		"cache":         "shared",
+		"_journal_mode": "WAL",
+		"_synchronous":  "NORMAL",
+		"_busy_timeout": "5000",
+		"_foreign_keys": "true",
+		"_temp_store":   "MEMORY",
+		"_auto_vacuum":  "FULL",
+	}
+	for key, value := range want {
+		if got := params.Get(key); got != value {
+			t.Errorf("params[%q] = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestBuildDSNParamsSkipsZeroBusyTimeout(t *testing.T) {
+	sqls := &Sqlite{config: &Config{BusyTimeoutMs: 0}}
+	params := sqls.buildDSNParams()
+	if params.Has("_busy_timeout") {
+		t.Fatalf("expected _busy_timeout to be omitted when unset, got %v", params)
+	}
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+// ---------------- JSON-friendly Querying ----------------
+// *sql.Rows isn't JSON-serializable and can't cross the Wails bindings, so
+// QueryJSON drains the result set into a plain struct the frontend can use.
+
+// QueryResult is a JSON-serializable snapshot of a query's result set.
+type QueryResult struct {
+	Columns     []string `json:"columns"`
+	ColumnTypes []string `json:"columnTypes"`
+	Rows        [][]any  `json:"rows"`
+}
+
+// QueryJSON runs query and returns its full result set as a QueryResult,
+// suitable for returning to the frontend across the Wails bindings.
+func (sqls Sqlite) QueryJSON(query string, args ...any) (QueryResult, error) {
+	rows, err := sqls.config.DB.Query(query, args...)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return QueryResult{}, err
+	}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return QueryResult{}, err
+	}
+	typeNames := make([]string, len(columnTypes))
+	for i, ct := range columnTypes {
+		typeNames[i] = ct.DatabaseTypeName()
+	}
+
+	result := QueryResult{Columns: columns, ColumnTypes: typeNames}
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return QueryResult{}, err
+		}
+		row := make([]any, len(columns))
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				row[i] = string(b)
+			} else {
+				row[i] = v
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return QueryResult{}, err
+	}
+
+	return result, nil
+}